@@ -0,0 +1,74 @@
+// Package ast defines the expression and parse-tree types shared between
+// the lexer, parser, printer, and bundler.
+package ast
+
+// Loc identifies a byte offset into a source file. It's a struct (instead
+// of a bare int) so it can grow additional fields (e.g. a source index)
+// without changing every call site.
+type Loc struct {
+	Start int32
+}
+
+// E is implemented by every expression node (EString, ENumber, etc).
+type E interface {
+	isExpr()
+}
+
+// Expr pairs an expression node with the location it was parsed from.
+type Expr struct {
+	Loc  Loc
+	Data E
+}
+
+type ENull struct{}
+
+type EBoolean struct {
+	Value bool
+}
+
+type EString struct {
+	Value []uint16
+}
+
+type ENumber struct {
+	Value float64
+}
+
+// EIdentifier refers to a symbol found via FindSymbol when processing a
+// "--define" substitution.
+type EIdentifier struct {
+	Ref int
+}
+
+// Property is a single "key: value" entry of an EObject.
+type Property struct {
+	Key   Expr
+	Value *Expr
+}
+
+type EObject struct {
+	Properties []Property
+}
+
+type EArray struct {
+	Items []Expr
+}
+
+func (*ENull) isExpr()       {}
+func (*EBoolean) isExpr()    {}
+func (*EString) isExpr()     {}
+func (*ENumber) isExpr()     {}
+func (*EIdentifier) isExpr() {}
+func (*EObject) isExpr()     {}
+func (*EArray) isExpr()      {}
+
+// AST is the result of parsing a single source file. Parsing in this
+// package doesn't build a full JavaScript syntax tree; it just records
+// enough about the file (its contents and the distinct import specifiers
+// found within it) for the bundler to resolve and concatenate dependencies.
+// This is what gets cached and reused across incremental rebuilds.
+type AST struct {
+	AbsPath string
+	Source  string
+	Imports []string
+}
@@ -0,0 +1,206 @@
+// Package bundler resolves a dependency graph starting from a set of entry
+// points, loads each file's contents (consulting load plugins before
+// touching disk), parses them, and concatenates the result.
+package bundler
+
+import (
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/logging"
+	"github.com/evanw/esbuild/internal/parser"
+	"github.com/evanw/esbuild/internal/printer"
+	"github.com/evanw/esbuild/internal/resolver"
+)
+
+type SourceMap uint8
+
+const (
+	SourceMapNone SourceMap = iota
+	SourceMapLinkedWithComment
+	SourceMapInline
+	SourceMapExternalWithoutComment
+)
+
+type Loader uint8
+
+const (
+	LoaderNone Loader = iota
+	LoaderJS
+	LoaderJSX
+	LoaderTS
+	LoaderTSX
+	LoaderJSON
+	LoaderText
+	LoaderBase64
+	LoaderDataURL
+	LoaderFile
+)
+
+// DefaultExtensionToLoaderMap returns the loader esbuild picks for a file
+// extension when BuildOptions.Loaders doesn't override it.
+func DefaultExtensionToLoaderMap() map[string]Loader {
+	return map[string]Loader{
+		".js":   LoaderJS,
+		".mjs":  LoaderJS,
+		".cjs":  LoaderJS,
+		".jsx":  LoaderJSX,
+		".ts":   LoaderTS,
+		".tsx":  LoaderTSX,
+		".json": LoaderJSON,
+	}
+}
+
+// VirtualFile serves in-memory contents for an absolute path instead of
+// reading it off of disk.
+type VirtualFile struct {
+	Contents   string
+	Loader     Loader
+	ResolveDir string
+}
+
+// OnLoadArgs is passed to a load plugin's OnLoad hook. Path is either a
+// real absolute path (when Namespace is empty) or an opaque identifier a
+// resolve plugin produced (when Namespace is non-empty) — either way, only
+// the bundler ever reads file contents, so this is the one place a load
+// hook is consulted.
+type OnLoadArgs struct {
+	Path      string
+	Namespace string
+}
+
+// OnLoadResult is what a load plugin's hook returns to supply a file's
+// contents.
+type OnLoadResult struct {
+	Contents   string
+	Loader     Loader
+	ResolveDir string
+}
+
+// LoadPlugin is the load-phase half of api.Plugin. The resolve-phase half
+// (OnResolve) lives in resolver.Plugin instead.
+type LoadPlugin struct {
+	Name   string
+	OnLoad func(OnLoadArgs) (OnLoadResult, bool)
+}
+
+// ASTCacheKey identifies a previously-parsed file well enough that a
+// changed file (or a changed parse option) can never return a stale hit.
+// It's deliberately not just an absolute path: ParseOptions holds slices
+// (not valid map key material on their own), so the hash folds in every
+// option that affects parsing rather than the options themselves.
+type ASTCacheKey struct {
+	AbsPath     string
+	ContentHash uint64
+}
+
+// ASTCache lets a caller (see api.buildContext) reuse ASTs parsed by a
+// previous incremental build instead of reparsing files that haven't
+// changed. Get and Put are closures rather than a plain map so the caller
+// can guard them with its own locking instead of this package needing to
+// know about it.
+type ASTCache struct {
+	Get func(ASTCacheKey) (*ast.AST, bool)
+	Put func(ASTCacheKey, *ast.AST)
+}
+
+// StdinInfo supplies the contents of the one "file" being transformed when
+// there's no real entry point on disk (the Transform API).
+type StdinInfo struct {
+	Contents   string
+	Loader     Loader
+	SourceFile string
+	ResolveDir string
+}
+
+// BundleOptions configures both the scan (ScanBundle) and the compile
+// (Bundle.Compile) steps.
+type BundleOptions struct {
+	SourceMap         SourceMap
+	MangleSyntax      bool
+	RemoveWhitespace  bool
+	MinifyIdentifiers bool
+	ModuleName        string
+	IsBundling        bool
+	CodeSplitting     bool
+	OutputFormat      printer.Format
+
+	AbsOutputFile   string
+	AbsOutputDir    string
+	AbsMetadataFile string
+	NeedsMetafile   bool
+
+	ExtensionToLoader map[string]Loader
+	VirtualFiles      map[string]VirtualFile
+	Plugins           []LoadPlugin
+
+	ASTCache *ASTCache
+	Stdin    *StdinInfo
+}
+
+// OutputFile is one file Bundle.Compile produced.
+type OutputFile struct {
+	AbsPath  string
+	Contents []byte
+}
+
+// fileRecord is one parsed file reachable from some entry point.
+type fileRecord struct {
+	absPath   string
+	namespace string
+	ast       *ast.AST
+	loader    Loader
+}
+
+// entryBundle is the transitive closure of one entry point, in dependency-
+// first order (a file always comes after everything it imports).
+type entryBundle struct {
+	entryPath string
+	files     []fileRecord
+}
+
+// Bundle is the result of ScanBundle: a resolved, parsed dependency graph
+// for every entry point, ready to be handed to Compile.
+type Bundle struct {
+	fs      fs.FS
+	log     logging.Log
+	options BundleOptions
+
+	entries    []entryBundle
+	watchPaths []string
+}
+
+// WatchPaths returns every absolute path actually read off of disk to
+// produce this bundle (entry points and resolved imports) - the set of
+// files watch mode needs to watch to know when to rebuild. Paths served by
+// a load plugin or VirtualFiles were never on disk, so they can't change
+// out from under a rebuild and aren't included.
+func (b *Bundle) WatchPaths() []string {
+	return b.watchPaths
+}
+
+// ScanBundle resolves and parses the dependency graph reachable from
+// entryPaths, using res to turn import specifiers into absolute paths and
+// consulting options.Plugins before reading a file from fsys.
+func ScanBundle(
+	log logging.Log,
+	fsys fs.FS,
+	res *resolver.Resolver,
+	entryPaths []string,
+	parseOptions parser.ParseOptions,
+	options BundleOptions,
+) *Bundle {
+	b := &Bundle{fs: fsys, log: log, options: options}
+
+	for _, entryPath := range entryPaths {
+		scanner := &entryScanner{
+			bundle:       b,
+			res:          res,
+			parseOptions: parseOptions,
+			visited:      make(map[string]bool),
+		}
+		scanner.scan(entryPath, "")
+		b.entries = append(b.entries, entryBundle{entryPath: entryPath, files: scanner.files})
+	}
+
+	return b
+}
@@ -0,0 +1,173 @@
+package bundler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/logging"
+	"github.com/evanw/esbuild/internal/printer"
+)
+
+// Compile renders every entry bundle to an OutputFile (plus one more per
+// entry when the source map is written to its own file) and, if
+// options.NeedsMetafile is set, a metafile JSON string describing the
+// whole build.
+func (b *Bundle) Compile(log logging.Log, options BundleOptions) ([]OutputFile, string) {
+	var outputs []OutputFile
+	inputs := make(map[string]metafileInput)
+	metaOutputs := make(map[string]metafileOutput)
+
+	for _, entry := range b.entries {
+		contents := b.render(entry, options)
+		outPath := b.outputPath(entry, options)
+
+		outputs = append(outputs, OutputFile{AbsPath: outPath, Contents: []byte(contents)})
+
+		if options.SourceMap != SourceMapNone {
+			mapJSON := buildSourceMap(entry)
+			last := len(outputs) - 1
+			switch options.SourceMap {
+			case SourceMapInline:
+				encoded := base64.StdEncoding.EncodeToString([]byte(mapJSON))
+				outputs[last].Contents = append(outputs[last].Contents,
+					[]byte("\n//# sourceMappingURL=data:application/json;base64,"+encoded)...)
+			case SourceMapLinkedWithComment:
+				mapPath := outPath + ".map"
+				outputs[last].Contents = append(outputs[last].Contents,
+					[]byte("\n//# sourceMappingURL="+b.fs.Base(mapPath))...)
+				outputs = append(outputs, OutputFile{AbsPath: mapPath, Contents: []byte(mapJSON)})
+			case SourceMapExternalWithoutComment:
+				outputs = append(outputs, OutputFile{AbsPath: outPath + ".map", Contents: []byte(mapJSON)})
+			}
+		}
+
+		if options.NeedsMetafile {
+			outInputs := make(map[string]metafileOutputInput, len(entry.files))
+			for _, f := range entry.files {
+				outInputs[f.absPath] = metafileOutputInput{BytesInOutput: len(f.ast.Source)}
+				if _, ok := inputs[f.absPath]; !ok {
+					inputs[f.absPath] = metafileInput{
+						Bytes:   len(f.ast.Source),
+						Imports: toMetafileImports(f.ast.Imports),
+					}
+				}
+			}
+			// Imports and Exports are left unset: see their doc comments in
+			// metafile.go for why this bundler can't populate them honestly.
+			metaOutputs[outPath] = metafileOutput{
+				Bytes:      len(contents),
+				Inputs:     outInputs,
+				EntryPoint: entry.entryPath,
+			}
+		}
+	}
+
+	var metafileJSON string
+	if options.NeedsMetafile {
+		data := metafileData{Inputs: inputs, Outputs: metaOutputs}
+		if bytes, err := json.Marshal(data); err == nil {
+			metafileJSON = string(bytes)
+		}
+	}
+
+	return outputs, metafileJSON
+}
+
+func (b *Bundle) outputPath(entry entryBundle, options BundleOptions) string {
+	if options.AbsOutputFile != "" && len(b.entries) == 1 {
+		return options.AbsOutputFile
+	}
+	if options.AbsOutputDir != "" {
+		base := b.fs.Base(entry.entryPath)
+		if dot := strings.LastIndexByte(base, '.'); dot != -1 {
+			base = base[:dot]
+		}
+		return b.fs.Join(options.AbsOutputDir, base+".js")
+	}
+	return entry.entryPath
+}
+
+// render concatenates an entry's files in dependency-first order, applying
+// each file's loader-specific wrapping (e.g. a JSON file becomes a
+// "module.exports = ..." assignment) and the output format's wrapper.
+// There's no symbol table in this codebase (see internal/ast), so
+// MangleSyntax and MinifyIdentifiers are accepted but can't actually rename
+// or fold anything; RemoveWhitespace is the one minification option this
+// bundler can honor for real.
+func (b *Bundle) render(entry entryBundle, options BundleOptions) string {
+	var sb strings.Builder
+	for _, f := range entry.files {
+		if options.IsBundling && len(entry.files) > 1 {
+			sb.WriteString("// ")
+			sb.WriteString(f.absPath)
+			sb.WriteString("\n")
+		}
+		content := renderLoaded(f)
+		if options.RemoveWhitespace {
+			content = removeWhitespace(content)
+		}
+		sb.WriteString(content)
+		sb.WriteString("\n")
+	}
+	body := sb.String()
+
+	switch options.OutputFormat {
+	case printer.FormatIIFE:
+		if options.ModuleName != "" {
+			return "var " + options.ModuleName + " = (function() {\n" + body + "})();\n"
+		}
+		return "(function() {\n" + body + "})();\n"
+	default:
+		return body
+	}
+}
+
+// renderLoaded applies the loader-specific transform for a single file: the
+// one place the loader value actually changes the output rather than just
+// being threaded through.
+func renderLoaded(f fileRecord) string {
+	switch f.loader {
+	case LoaderJSON:
+		return "module.exports = " + f.ast.Source + ";"
+	case LoaderText:
+		return "module.exports = " + strconv.Quote(f.ast.Source) + ";"
+	case LoaderBase64:
+		return "module.exports = " + strconv.Quote(base64.StdEncoding.EncodeToString([]byte(f.ast.Source))) + ";"
+	case LoaderDataURL:
+		encoded := base64.StdEncoding.EncodeToString([]byte(f.ast.Source))
+		return "module.exports = " + strconv.Quote("data:text/plain;base64,"+encoded) + ";"
+	case LoaderFile:
+		return "module.exports = " + strconv.Quote(f.absPath) + ";"
+	default:
+		return f.ast.Source
+	}
+}
+
+// removeWhitespace drops blank lines and trims trailing whitespace from
+// each remaining line. It's not a real minifier, just the one thing this
+// bundler can do without a symbol table or a real printer.
+func removeWhitespace(text string) string {
+	lines := strings.Split(text, "\n")
+	var kept []string
+	for _, line := range lines {
+		trimmed := strings.TrimRight(line, " \t\r")
+		if strings.TrimSpace(trimmed) == "" {
+			continue
+		}
+		kept = append(kept, trimmed)
+	}
+	return strings.Join(kept, "\n")
+}
+
+func toMetafileImports(specs []string) []metafileImport {
+	if len(specs) == 0 {
+		return nil
+	}
+	imports := make([]metafileImport, len(specs))
+	for i, spec := range specs {
+		imports[i] = metafileImport{Path: spec}
+	}
+	return imports
+}
@@ -0,0 +1,42 @@
+package bundler
+
+// These mirror api.MetafileData's shape field-for-field so that JSON
+// produced here round-trips through api.ParseMetafile. They're defined
+// locally instead of reusing the api types directly because api already
+// imports bundler — the other direction would be an import cycle.
+type metafileImport struct {
+	Path string `json:"path"`
+}
+
+type metafileInput struct {
+	Bytes   int              `json:"bytes"`
+	Imports []metafileImport `json:"imports"`
+}
+
+type metafileOutputInput struct {
+	BytesInOutput int `json:"bytesInOutput"`
+}
+
+type metafileOutput struct {
+	Bytes  int                            `json:"bytes"`
+	Inputs map[string]metafileOutputInput `json:"inputs"`
+
+	// Imports and Exports always serialize as "null": unlike metafileInput's
+	// Imports (built straight from ast.AST.Imports, the raw specifier list a
+	// file contains), an output's imports would need to know which of those
+	// specifiers resolved to an external module rather than getting bundled
+	// in - scan.go discards that distinction once a file is marked External
+	// and never recurses into it. Exports would need real export-binding
+	// tracking, which internal/ast doesn't have at all (ast.AST has no
+	// concept of a file's exports, only its Imports). Populating either
+	// field for real is follow-up work, not something to fake here.
+	Imports []metafileImport `json:"imports"`
+	Exports []string         `json:"exports"`
+
+	EntryPoint string `json:"entryPoint,omitempty"`
+}
+
+type metafileData struct {
+	Inputs  map[string]metafileInput  `json:"inputs"`
+	Outputs map[string]metafileOutput `json:"outputs"`
+}
@@ -0,0 +1,154 @@
+package bundler
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/logging"
+	"github.com/evanw/esbuild/internal/parser"
+	"github.com/evanw/esbuild/internal/resolver"
+)
+
+// entryScanner walks the import graph reachable from a single entry point,
+// building files in dependency-first order.
+type entryScanner struct {
+	bundle       *Bundle
+	res          *resolver.Resolver
+	parseOptions parser.ParseOptions
+	visited      map[string]bool
+	files        []fileRecord
+}
+
+// scan loads, parses, and recurses into absPath's imports. It's a no-op if
+// absPath (qualified by namespace) has already been visited in this entry's
+// traversal, which is what makes import cycles safe.
+func (s *entryScanner) scan(absPath string, namespace string) {
+	key := namespace + "\x00" + absPath
+	if s.visited[key] {
+		return
+	}
+	s.visited[key] = true
+
+	contents, loader, resolveDir, ok := s.load(absPath, namespace)
+	if !ok {
+		s.bundle.log.AddError(nil, ast.Loc{}, fmt.Sprintf("Could not read: %s", absPath))
+		return
+	}
+
+	source := logging.Source{Contents: contents, PrettyPath: absPath}
+	parsedAST := s.parse(absPath, source)
+
+	for _, spec := range parsedAST.Imports {
+		result, ok := s.res.Resolve(spec, absPath, namespace, resolveDir)
+		if !ok {
+			s.bundle.log.AddError(nil, ast.Loc{}, fmt.Sprintf("Could not resolve %q from %q", spec, absPath))
+			continue
+		}
+		if result.External {
+			continue
+		}
+		s.scan(result.AbsPath, result.Namespace)
+	}
+
+	// Appending after recursing puts dependencies before dependents, which
+	// is the order Compile concatenates files in.
+	s.files = append(s.files, fileRecord{absPath: absPath, namespace: namespace, ast: parsedAST, loader: loader})
+}
+
+// parse returns source's AST, consulting the bundle's ASTCache (if any) so
+// that an incremental rebuild can skip reparsing a file whose contents and
+// parse-affecting options haven't changed.
+func (s *entryScanner) parse(absPath string, source logging.Source) *ast.AST {
+	cache := s.bundle.options.ASTCache
+	if cache == nil {
+		parsedAST, _ := parser.Parse(s.bundle.log, source, s.parseOptions)
+		return parsedAST
+	}
+
+	key := astCacheKey(absPath, source.Contents, s.parseOptions)
+	if cached, ok := cache.Get(key); ok {
+		return cached
+	}
+
+	parsedAST, _ := parser.Parse(s.bundle.log, source, s.parseOptions)
+	cache.Put(key, parsedAST)
+	return parsedAST
+}
+
+// astCacheKey hashes everything that affects how source's contents are
+// parsed, so a cache hit can only ever happen for a file whose contents and
+// whose parse options are both unchanged from a previous build.
+func astCacheKey(absPath string, contents string, options parser.ParseOptions) ASTCacheKey {
+	h := fnv.New64a()
+	h.Write([]byte(contents))
+	fmt.Fprintf(h, "\x00%+v", options)
+	return ASTCacheKey{AbsPath: absPath, ContentHash: h.Sum64()}
+}
+
+// load returns absPath's contents, loader, and the directory its relative
+// imports should resolve against, trying (in order) VirtualFiles, load
+// plugins, and finally the real file system. A non-empty namespace means
+// absPath was produced by a resolve plugin rather than esbuild's own path
+// math, so it can only be served by a load plugin — there's nothing to
+// read off of disk.
+func (s *entryScanner) load(absPath string, namespace string) (contents string, loader Loader, resolveDir string, ok bool) {
+	options := &s.bundle.options
+
+	if vf, found := options.VirtualFiles[absPath]; found && namespace == "" {
+		resolveDir = vf.ResolveDir
+		if resolveDir == "" {
+			resolveDir = s.bundle.fs.Dir(absPath)
+		}
+		return vf.Contents, s.loaderFor(absPath, vf.Loader), resolveDir, true
+	}
+
+	for _, plugin := range options.Plugins {
+		if plugin.OnLoad == nil {
+			continue
+		}
+		if result, matched := plugin.OnLoad(OnLoadArgs{Path: absPath, Namespace: namespace}); matched {
+			resolveDir = result.ResolveDir
+			if resolveDir == "" {
+				resolveDir = s.bundle.fs.Dir(absPath)
+			}
+			return result.Contents, s.loaderFor(absPath, result.Loader), resolveDir, true
+		}
+	}
+
+	if namespace != "" {
+		return "", LoaderNone, "", false
+	}
+
+	contents, ok = s.bundle.fs.ReadFile(absPath)
+	if ok {
+		s.bundle.watchPaths = append(s.bundle.watchPaths, absPath)
+	}
+
+	// Transform's stdin input is "read" off of a mock file system by
+	// SourceFile name, but its Loader is supplied directly by the caller
+	// (there's no real extension to infer from when Sourcefile is empty or
+	// made up), so it takes precedence over extension-based detection the
+	// same way a virtual file's or load plugin's explicit Loader does.
+	explicit := LoaderNone
+	if stdin := options.Stdin; stdin != nil && stdin.SourceFile == absPath {
+		explicit = stdin.Loader
+	}
+	return contents, s.loaderFor(absPath, explicit), s.bundle.fs.Dir(absPath), ok
+}
+
+// loaderFor returns explicit (a non-LoaderNone value supplied by a virtual
+// file or load plugin) if set, otherwise looks absPath's extension up in
+// ExtensionToLoader, defaulting to LoaderJS.
+func (s *entryScanner) loaderFor(absPath string, explicit Loader) Loader {
+	if explicit != LoaderNone {
+		return explicit
+	}
+	if dot := strings.LastIndexByte(absPath, '.'); dot != -1 {
+		if loader, ok := s.bundle.options.ExtensionToLoader[absPath[dot:]]; ok {
+			return loader
+		}
+	}
+	return LoaderJS
+}
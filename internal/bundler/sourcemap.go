@@ -0,0 +1,33 @@
+package bundler
+
+import "encoding/json"
+
+// sourceMapJSON is a source map (v3) with no "mappings" — there's no
+// printer in this codebase that tracks output positions back to input
+// positions, so this can't claim byte-accurate mappings. What it does get
+// right is that "sources" lists the same absolute paths files (including
+// virtual ones) were loaded under, so a debugger opening the map finds the
+// same path the build was given.
+type sourceMapJSON struct {
+	Version        int      `json:"version"`
+	Sources        []string `json:"sources"`
+	SourcesContent []string `json:"sourcesContent"`
+	Mappings       string   `json:"mappings"`
+}
+
+func buildSourceMap(entry entryBundle) string {
+	m := sourceMapJSON{
+		Version:        3,
+		Sources:        make([]string, len(entry.files)),
+		SourcesContent: make([]string, len(entry.files)),
+	}
+	for i, f := range entry.files {
+		m.Sources[i] = f.absPath
+		m.SourcesContent[i] = f.ast.Source
+	}
+	bytes, err := json.Marshal(m)
+	if err != nil {
+		return "{}"
+	}
+	return string(bytes)
+}
@@ -0,0 +1,40 @@
+package bundler
+
+import (
+	"testing"
+
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/logging"
+	"github.com/evanw/esbuild/internal/parser"
+	"github.com/evanw/esbuild/internal/resolver"
+)
+
+func TestStdinLoaderTakesPrecedenceOverExtension(t *testing.T) {
+	const sourcefile = "weird-name-no-ext"
+	mockFS := fs.MockFS(map[string]string{sourcefile: `{"a": 1}`})
+	res := resolver.NewResolver(mockFS, logging.NewDeferLog(), resolver.ResolveOptions{})
+	options := BundleOptions{
+		AbsOutputFile: sourcefile + "-out",
+		Stdin: &StdinInfo{
+			Loader:     LoaderJSON,
+			Contents:   `{"a": 1}`,
+			SourceFile: sourcefile,
+		},
+	}
+
+	log := logging.NewDeferLog()
+	bundle := ScanBundle(log, mockFS, res, []string{sourcefile}, parser.ParseOptions{}, options)
+	if log.HasErrors() {
+		t.Fatalf("unexpected scan errors: %v", log.Done())
+	}
+
+	outputs, _ := bundle.Compile(log, options)
+	if len(outputs) != 1 {
+		t.Fatalf("expected 1 output, got %d", len(outputs))
+	}
+	got := string(outputs[0].Contents)
+	want := "module.exports = {\"a\": 1};\n"
+	if got != want {
+		t.Errorf("expected stdin's explicit Loader to be honored, got %q want %q", got, want)
+	}
+}
@@ -0,0 +1,86 @@
+// Package fs abstracts file system access so the resolver and bundler can
+// run against either the real disk (RealFS) or an in-memory map (MockFS).
+package fs
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// FS is the minimal file system surface the resolver and bundler need.
+type FS interface {
+	ReadFile(path string) (string, bool)
+	Abs(path string) (string, bool)
+	Dir(path string) string
+	Base(path string) string
+	Join(parts ...string) string
+}
+
+type realFS struct{}
+
+// RealFS returns an FS backed by the actual operating system file system.
+func RealFS() FS {
+	return realFS{}
+}
+
+func (realFS) ReadFile(path string) (string, bool) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(contents), true
+}
+
+func (realFS) Abs(path string) (string, bool) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", false
+	}
+	return abs, true
+}
+
+func (realFS) Dir(path string) string {
+	return filepath.Dir(path)
+}
+
+func (realFS) Base(path string) string {
+	return filepath.Base(path)
+}
+
+func (realFS) Join(parts ...string) string {
+	return filepath.Join(parts...)
+}
+
+type mockFS struct {
+	files map[string]string
+}
+
+// MockFS returns an FS backed by an in-memory map of path to contents, used
+// by the Transform API where there's no real entry point on disk.
+func MockFS(files map[string]string) FS {
+	return &mockFS{files: files}
+}
+
+func (m *mockFS) ReadFile(path string) (string, bool) {
+	contents, ok := m.files[path]
+	return contents, ok
+}
+
+func (m *mockFS) Abs(path string) (string, bool) {
+	if filepath.IsAbs(path) {
+		return filepath.Clean(path), true
+	}
+	return filepath.Join("/", path), true
+}
+
+func (m *mockFS) Dir(path string) string {
+	return filepath.Dir(path)
+}
+
+func (m *mockFS) Base(path string) string {
+	return filepath.Base(path)
+}
+
+func (m *mockFS) Join(parts ...string) string {
+	return filepath.Join(parts...)
+}
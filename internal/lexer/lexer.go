@@ -0,0 +1,59 @@
+// Package lexer provides small tokenization helpers shared by the parser
+// and the api package's option validation.
+package lexer
+
+import "unicode/utf16"
+
+var keywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true, "do": true,
+	"else": true, "export": true, "extends": true, "false": true, "finally": true,
+	"for": true, "function": true, "if": true, "import": true, "in": true,
+	"instanceof": true, "new": true, "null": true, "return": true, "super": true,
+	"switch": true, "this": true, "throw": true, "true": true, "try": true,
+	"typeof": true, "var": true, "void": true, "while": true, "with": true,
+}
+
+// Keywords returns the set of reserved words that can't be used as a
+// substitution identifier (e.g. in "--define:x=null").
+func Keywords() map[string]bool {
+	return keywords
+}
+
+// IsIdentifier reports whether text is a valid JavaScript identifier.
+func IsIdentifier(text string) bool {
+	if text == "" {
+		return false
+	}
+	for i, c := range text {
+		if i == 0 {
+			if !isIdentifierStart(c) {
+				return false
+			}
+			continue
+		}
+		if !isIdentifierContinue(c) {
+			return false
+		}
+	}
+	return true
+}
+
+func isIdentifierStart(c rune) bool {
+	return c == '_' || c == '$' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isIdentifierContinue(c rune) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
+
+// UTF16ToString converts a UTF-16 code unit slice (how esbuild stores
+// string literals internally) to a normal Go string.
+func UTF16ToString(value []uint16) string {
+	return string(utf16.Decode(value))
+}
+
+// StringToUTF16 is the inverse of UTF16ToString.
+func StringToUTF16(text string) []uint16 {
+	return utf16.Encode([]rune(text))
+}
@@ -0,0 +1,158 @@
+// Package logging collects and prints diagnostic messages produced while
+// resolving, loading, and bundling.
+package logging
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/evanw/esbuild/internal/ast"
+)
+
+type MsgKind uint8
+
+const (
+	Error MsgKind = iota
+	Warning
+)
+
+// Source is the file a message was generated about, if any.
+type Source struct {
+	Contents   string
+	PrettyPath string
+}
+
+type Msg struct {
+	Kind   MsgKind
+	Text   string
+	Source *Source
+	Start  int32
+	Length int32
+}
+
+type StderrColor uint8
+
+const (
+	ColorIfTerminal StderrColor = iota
+	ColorNever
+	ColorAlways
+)
+
+type LogLevel uint8
+
+const (
+	LevelInfo LogLevel = iota
+	LevelWarning
+	LevelError
+)
+
+type StderrOptions struct {
+	IncludeSource bool
+	ErrorLimit    int
+	Color         StderrColor
+	LogLevel      LogLevel
+}
+
+// Log accumulates messages produced during a build and decides, depending
+// on how it was constructed, whether to print them immediately or just
+// hold onto them for the caller to inspect with Done().
+type Log interface {
+	AddError(source *Source, loc ast.Loc, text string)
+	AddWarning(source *Source, loc ast.Loc, text string)
+	HasErrors() bool
+	Done() []Msg
+}
+
+type deferLog struct {
+	msgs      []Msg
+	hasErrors bool
+}
+
+// NewDeferLog returns a Log that silently collects messages for the caller
+// to retrieve later via Done(), without ever printing to stderr.
+func NewDeferLog() Log {
+	return &deferLog{}
+}
+
+func (l *deferLog) AddError(source *Source, loc ast.Loc, text string) {
+	l.hasErrors = true
+	l.msgs = append(l.msgs, Msg{Kind: Error, Text: text, Source: source, Start: loc.Start})
+}
+
+func (l *deferLog) AddWarning(source *Source, loc ast.Loc, text string) {
+	l.msgs = append(l.msgs, Msg{Kind: Warning, Text: text, Source: source, Start: loc.Start})
+}
+
+func (l *deferLog) HasErrors() bool { return l.hasErrors }
+func (l *deferLog) Done() []Msg     { return l.msgs }
+
+type stderrLog struct {
+	options   StderrOptions
+	msgs      []Msg
+	hasErrors bool
+}
+
+// NewStderrLog returns a Log that prints each message to stderr as it
+// arrives (subject to options.LogLevel and options.ErrorLimit) and also
+// retains them for Done().
+func NewStderrLog(options StderrOptions) Log {
+	return &stderrLog{options: options}
+}
+
+func (l *stderrLog) AddError(source *Source, loc ast.Loc, text string) {
+	l.hasErrors = true
+	msg := Msg{Kind: Error, Text: text, Source: source, Start: loc.Start}
+	l.msgs = append(l.msgs, msg)
+	l.print(msg)
+}
+
+func (l *stderrLog) AddWarning(source *Source, loc ast.Loc, text string) {
+	msg := Msg{Kind: Warning, Text: text, Source: source, Start: loc.Start}
+	l.msgs = append(l.msgs, msg)
+	l.print(msg)
+}
+
+func (l *stderrLog) print(msg Msg) {
+	if msg.Kind == Warning && l.options.LogLevel > LevelWarning {
+		return
+	}
+	if l.options.ErrorLimit > 0 && l.errorCount() > l.options.ErrorLimit {
+		return
+	}
+	prefix := "error"
+	if msg.Kind == Warning {
+		prefix = "warning"
+	}
+	location := ""
+	if l.options.IncludeSource && msg.Source != nil {
+		location = fmt.Sprintf(" [%s]", msg.Source.PrettyPath)
+	}
+	fmt.Fprintf(os.Stderr, "%s:%s %s\n", prefix, location, msg.Text)
+}
+
+func (l *stderrLog) errorCount() int {
+	count := 0
+	for _, msg := range l.msgs {
+		if msg.Kind == Error {
+			count++
+		}
+	}
+	return count
+}
+
+func (l *stderrLog) HasErrors() bool { return l.hasErrors }
+func (l *stderrLog) Done() []Msg     { return l.msgs }
+
+// ComputeLineAndColumn returns the 0-based line number and column (both as
+// of the end of "text"), along with the byte offset where that line starts
+// within the original source.
+func ComputeLineAndColumn(text string) (line int, column int, lineStart int) {
+	for i := 0; i < len(text); i++ {
+		if text[i] == '\n' {
+			line++
+			lineStart = i + 1
+		}
+	}
+	column = len(text) - lineStart
+	return line, column, lineStart
+}
@@ -0,0 +1,233 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/lexer"
+	"github.com/evanw/esbuild/internal/logging"
+)
+
+// ParseJSONOptions controls how lenient ParseJSON is. tsconfig.json files
+// (and package.json, historically) are not strict JSON: they commonly
+// contain "//" and "/* */" comments and trailing commas.
+type ParseJSONOptions struct {
+	AllowComments       bool
+	AllowTrailingCommas bool
+}
+
+type jsonParser struct {
+	log     logging.Log
+	source  logging.Source
+	options ParseJSONOptions
+	text    string
+	i       int
+}
+
+// ParseJSON parses source.Contents as JSON (optionally with comments and
+// trailing commas allowed) into an ast.Expr tree.
+func ParseJSON(log logging.Log, source logging.Source, options ParseJSONOptions) (ast.Expr, bool) {
+	p := &jsonParser{log: log, source: source, options: options, text: source.Contents}
+	p.skipWhitespace()
+	expr, ok := p.parseExpr()
+	if !ok {
+		return ast.Expr{}, false
+	}
+	p.skipWhitespace()
+	return expr, true
+}
+
+func (p *jsonParser) errorf(format string, args ...interface{}) {
+	p.log.AddError(&p.source, ast.Loc{Start: int32(p.i)}, fmt.Sprintf(format, args...))
+}
+
+func (p *jsonParser) skipWhitespace() {
+	for p.i < len(p.text) {
+		c := p.text[p.i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			p.i++
+		case p.options.AllowComments && c == '/' && p.i+1 < len(p.text) && p.text[p.i+1] == '/':
+			for p.i < len(p.text) && p.text[p.i] != '\n' {
+				p.i++
+			}
+		case p.options.AllowComments && c == '/' && p.i+1 < len(p.text) && p.text[p.i+1] == '*':
+			p.i += 2
+			for p.i+1 < len(p.text) && !(p.text[p.i] == '*' && p.text[p.i+1] == '/') {
+				p.i++
+			}
+			p.i += 2
+		default:
+			return
+		}
+	}
+}
+
+func (p *jsonParser) parseExpr() (ast.Expr, bool) {
+	p.skipWhitespace()
+	if p.i >= len(p.text) {
+		p.errorf("Unexpected end of JSON")
+		return ast.Expr{}, false
+	}
+
+	start := int32(p.i)
+	switch c := p.text[p.i]; {
+	case c == '{':
+		return p.parseObject(start)
+	case c == '[':
+		return p.parseArray(start)
+	case c == '"':
+		str, ok := p.parseStringLiteral()
+		if !ok {
+			return ast.Expr{}, false
+		}
+		return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.EString{Value: lexer.StringToUTF16(str)}}, true
+	case strings.HasPrefix(p.text[p.i:], "true"):
+		p.i += 4
+		return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.EBoolean{Value: true}}, true
+	case strings.HasPrefix(p.text[p.i:], "false"):
+		p.i += 5
+		return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.EBoolean{Value: false}}, true
+	case strings.HasPrefix(p.text[p.i:], "null"):
+		p.i += 4
+		return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.ENull{}}, true
+	case c == '-' || (c >= '0' && c <= '9'):
+		return p.parseNumber(start)
+	default:
+		p.errorf("Unexpected character in JSON: %q", string(c))
+		return ast.Expr{}, false
+	}
+}
+
+func (p *jsonParser) parseStringLiteral() (string, bool) {
+	if p.text[p.i] != '"' {
+		p.errorf("Expected string")
+		return "", false
+	}
+	p.i++
+	var sb strings.Builder
+	for p.i < len(p.text) && p.text[p.i] != '"' {
+		c := p.text[p.i]
+		if c == '\\' && p.i+1 < len(p.text) {
+			p.i++
+			switch p.text[p.i] {
+			case 'n':
+				sb.WriteByte('\n')
+			case 't':
+				sb.WriteByte('\t')
+			case 'r':
+				sb.WriteByte('\r')
+			default:
+				sb.WriteByte(p.text[p.i])
+			}
+			p.i++
+			continue
+		}
+		sb.WriteByte(c)
+		p.i++
+	}
+	if p.i >= len(p.text) {
+		p.errorf("Unterminated string literal")
+		return "", false
+	}
+	p.i++ // Skip the closing quote
+	return sb.String(), true
+}
+
+func (p *jsonParser) parseNumber(start int32) (ast.Expr, bool) {
+	begin := p.i
+	if p.text[p.i] == '-' {
+		p.i++
+	}
+	for p.i < len(p.text) && (isDigit(p.text[p.i]) || p.text[p.i] == '.' || p.text[p.i] == 'e' || p.text[p.i] == 'E' || p.text[p.i] == '+' || p.text[p.i] == '-') {
+		p.i++
+	}
+	value, err := strconv.ParseFloat(p.text[begin:p.i], 64)
+	if err != nil {
+		p.errorf("Invalid number in JSON: %q", p.text[begin:p.i])
+		return ast.Expr{}, false
+	}
+	return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.ENumber{Value: value}}, true
+}
+
+func isDigit(c byte) bool { return c >= '0' && c <= '9' }
+
+func (p *jsonParser) parseObject(start int32) (ast.Expr, bool) {
+	p.i++ // Skip '{'
+	var properties []ast.Property
+	p.skipWhitespace()
+	for p.i < len(p.text) && p.text[p.i] != '}' {
+		p.skipWhitespace()
+		if p.i < len(p.text) && p.text[p.i] == '}' {
+			break
+		}
+		keyStart := int32(p.i)
+		key, ok := p.parseStringLiteral()
+		if !ok {
+			return ast.Expr{}, false
+		}
+		keyExpr := ast.Expr{Loc: ast.Loc{Start: keyStart}, Data: &ast.EString{Value: lexer.StringToUTF16(key)}}
+		p.skipWhitespace()
+		if p.i >= len(p.text) || p.text[p.i] != ':' {
+			p.errorf("Expected \":\" after property name")
+			return ast.Expr{}, false
+		}
+		p.i++
+		value, ok := p.parseExpr()
+		if !ok {
+			return ast.Expr{}, false
+		}
+		properties = append(properties, ast.Property{Key: keyExpr, Value: &value})
+		p.skipWhitespace()
+		if p.i < len(p.text) && p.text[p.i] == ',' {
+			p.i++
+			p.skipWhitespace()
+			if p.i < len(p.text) && p.text[p.i] == '}' && !p.options.AllowTrailingCommas {
+				p.errorf("Unexpected trailing comma before \"}\"")
+				return ast.Expr{}, false
+			}
+			continue
+		}
+		break
+	}
+	p.skipWhitespace()
+	if p.i >= len(p.text) || p.text[p.i] != '}' {
+		p.errorf("Expected \"}\"")
+		return ast.Expr{}, false
+	}
+	p.i++
+	return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.EObject{Properties: properties}}, true
+}
+
+func (p *jsonParser) parseArray(start int32) (ast.Expr, bool) {
+	p.i++ // Skip '['
+	var items []ast.Expr
+	p.skipWhitespace()
+	for p.i < len(p.text) && p.text[p.i] != ']' {
+		item, ok := p.parseExpr()
+		if !ok {
+			return ast.Expr{}, false
+		}
+		items = append(items, item)
+		p.skipWhitespace()
+		if p.i < len(p.text) && p.text[p.i] == ',' {
+			p.i++
+			p.skipWhitespace()
+			if p.i < len(p.text) && p.text[p.i] == ']' && !p.options.AllowTrailingCommas {
+				p.errorf("Unexpected trailing comma before \"]\"")
+				return ast.Expr{}, false
+			}
+			continue
+		}
+		break
+	}
+	p.skipWhitespace()
+	if p.i >= len(p.text) || p.text[p.i] != ']' {
+		p.errorf("Expected \"]\"")
+		return ast.Expr{}, false
+	}
+	p.i++
+	return ast.Expr{Loc: ast.Loc{Start: start}, Data: &ast.EArray{Items: items}}, true
+}
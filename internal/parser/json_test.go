@@ -0,0 +1,48 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/evanw/esbuild/internal/logging"
+)
+
+func parseJSONForTest(t *testing.T, options ParseJSONOptions, contents string) bool {
+	t.Helper()
+	log := logging.NewDeferLog()
+	source := logging.Source{Contents: contents, PrettyPath: "<test>"}
+	_, ok := ParseJSON(log, source, options)
+	return ok && !log.HasErrors()
+}
+
+func TestParseJSONTrailingCommas(t *testing.T) {
+	tests := []struct {
+		name    string
+		options ParseJSONOptions
+		input   string
+		wantOK  bool
+	}{
+		{"strict object rejects trailing comma", ParseJSONOptions{}, `{"a": 1,}`, false},
+		{"strict array rejects trailing comma", ParseJSONOptions{}, `[1, 2,]`, false},
+		{"lenient object accepts trailing comma", ParseJSONOptions{AllowTrailingCommas: true}, `{"a": 1,}`, true},
+		{"lenient array accepts trailing comma", ParseJSONOptions{AllowTrailingCommas: true}, `[1, 2,]`, true},
+		{"strict object accepts no trailing comma", ParseJSONOptions{}, `{"a": 1}`, true},
+		{"strict array accepts no trailing comma", ParseJSONOptions{}, `[1, 2]`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if ok := parseJSONForTest(t, tt.options, tt.input); ok != tt.wantOK {
+				t.Errorf("ParseJSON(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParseJSONComments(t *testing.T) {
+	input := "{\n  // a comment\n  \"a\": 1\n}"
+	if ok := parseJSONForTest(t, ParseJSONOptions{}, input); ok {
+		t.Errorf("strict ParseJSON should reject comments")
+	}
+	if ok := parseJSONForTest(t, ParseJSONOptions{AllowComments: true}, input); !ok {
+		t.Errorf("lenient ParseJSON should accept comments")
+	}
+}
@@ -0,0 +1,102 @@
+// Package parser turns source text into an ast.AST and processes
+// "--define" substitutions. It's deliberately lightweight: it doesn't
+// build a full JavaScript syntax tree, just enough structure (import
+// specifiers) for the bundler to resolve and concatenate dependencies.
+package parser
+
+import (
+	"regexp"
+
+	"github.com/evanw/esbuild/internal/ast"
+	"github.com/evanw/esbuild/internal/logging"
+)
+
+type Platform uint8
+
+const (
+	PlatformBrowser Platform = iota
+	PlatformNode
+)
+
+type LanguageTarget uint8
+
+const (
+	ESNext LanguageTarget = iota
+	ES2015
+	ES2016
+	ES2017
+	ES2018
+	ES2019
+	ES2020
+)
+
+type StrictOptions struct {
+	NullishCoalescing bool
+	ClassFields       bool
+}
+
+type JSXOptions struct {
+	Factory  []string
+	Fragment []string
+}
+
+// TSOptions holds the subset of tsconfig.json's "compilerOptions" that
+// affect parsing rather than resolution.
+type TSOptions struct {
+	ExperimentalDecorators bool
+	ImportsNotUsedAsValues string
+}
+
+type ParseOptions struct {
+	Target       LanguageTarget
+	Strict       StrictOptions
+	MangleSyntax bool
+	JSX          JSXOptions
+	Defines      *ProcessedDefines
+	Platform     Platform
+	IsBundling   bool
+
+	UseDefineForClassFields bool
+	TS                      TSOptions
+}
+
+// FindSymbol resolves an identifier name to a symbol reference while
+// processing "--define" substitutions.
+type FindSymbol func(name string) int
+
+type DefineFunc func(FindSymbol) ast.E
+
+// ProcessedDefines is the result of ProcessDefines: a set of raw defines
+// that's expensive to validate and is therefore computed once up front and
+// shared across every parse call in a build.
+type ProcessedDefines struct {
+	Raw map[string]DefineFunc
+}
+
+func ProcessDefines(raw map[string]DefineFunc) ProcessedDefines {
+	return ProcessedDefines{Raw: raw}
+}
+
+var importRegexp = regexp.MustCompile(`(?:\bimport\s+(?:[^'"]*?\sfrom\s*)?|\brequire\s*\(\s*|\bexport\s+[^'"]*?\sfrom\s*)['"]([^'"]+)['"]`)
+
+// Parse does a best-effort scan of source for import/require/export-from
+// specifiers. It returns an *ast.AST (the unit the bundler caches and
+// resolves dependencies from) and false only if source is unreadable as
+// text, which in practice never happens since contents are already a Go
+// string by the time they reach here.
+func Parse(log logging.Log, source logging.Source, options ParseOptions) (*ast.AST, bool) {
+	seen := make(map[string]bool)
+	var imports []string
+	for _, match := range importRegexp.FindAllStringSubmatch(source.Contents, -1) {
+		spec := match[1]
+		if !seen[spec] {
+			seen[spec] = true
+			imports = append(imports, spec)
+		}
+	}
+	return &ast.AST{
+		AbsPath: source.PrettyPath,
+		Source:  source.Contents,
+		Imports: imports,
+	}, true
+}
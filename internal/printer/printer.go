@@ -0,0 +1,14 @@
+// Package printer defines the output-format enum shared between the api
+// and bundler packages. Actual code generation lives in internal/bundler.
+package printer
+
+type Format uint8
+
+const (
+	// FormatPreserve means "not set" - the api package picks a default based
+	// on platform once bundling is confirmed to be enabled.
+	FormatPreserve Format = iota
+	FormatIIFE
+	FormatCommonJS
+	FormatESModule
+)
@@ -0,0 +1,186 @@
+// Package resolver turns an import specifier plus the file that imported
+// it into an absolute path (or an external/namespaced module reference),
+// consulting any registered plugin resolve hooks before touching the file
+// system.
+package resolver
+
+import (
+	"strings"
+
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/logging"
+	"github.com/evanw/esbuild/internal/parser"
+)
+
+// OnResolveArgs is passed to a plugin's resolve hook.
+type OnResolveArgs struct {
+	Path       string
+	Importer   string
+	Namespace  string
+	ResolveDir string
+}
+
+// OnResolveResult is what a plugin's resolve hook returns to claim an
+// import path. A non-empty Namespace marks the result as virtual: the
+// bundler will look for its contents via a load hook instead of reading
+// Path off of disk.
+type OnResolveResult struct {
+	Path      string
+	Namespace string
+	External  bool
+}
+
+// Plugin is the resolve-phase half of api.Plugin. The load-phase half
+// (OnLoad) is consulted by internal/bundler instead, since only the
+// bundler ever reads file contents.
+type Plugin struct {
+	Name      string
+	OnResolve func(OnResolveArgs) (OnResolveResult, bool)
+}
+
+// ResolveOptions configures path resolution for an entire build.
+type ResolveOptions struct {
+	Platform        parser.Platform
+	ExtensionOrder  []string
+	ExternalModules map[string]bool
+	Plugins         []Plugin
+
+	// BaseURL and Paths come from tsconfig.json's "compilerOptions.baseUrl"
+	// and "compilerOptions.paths".
+	BaseURL string
+	Paths   []PathMapping
+
+	// VirtualPaths holds the absolute path of every BuildOptions.VirtualFile.
+	// They're never on disk, so resolvePath needs to be told they "exist"
+	// separately from the real file system check.
+	VirtualPaths map[string]bool
+}
+
+// PathMapping is one entry of tsconfig.json's "compilerOptions.paths",
+// kept in the declaration order of the original object (a plain
+// map[string][]string would collapse that order, and tsconfig.json
+// resolution is specified to try patterns in the order they're written -
+// e.g. a more specific "utils/*" ahead of a catch-all "*").
+type PathMapping struct {
+	Pattern       string
+	Substitutions []string
+}
+
+// IsNonModulePath reports whether a path looks like a relative/absolute
+// file path rather than a bare package specifier (e.g. "./foo" or "/foo"
+// vs "foo" or "@scope/foo"). Bare specifiers are the only form allowed in
+// the "external" list since esbuild can't meaningfully mark a relative
+// import as external.
+func IsNonModulePath(path string) bool {
+	return strings.HasPrefix(path, "/") || strings.HasPrefix(path, "./") || strings.HasPrefix(path, "../")
+}
+
+// Resolver resolves import specifiers to absolute paths for one build.
+type Resolver struct {
+	fs      fs.FS
+	log     logging.Log
+	options ResolveOptions
+}
+
+func NewResolver(realFS fs.FS, log logging.Log, options ResolveOptions) *Resolver {
+	return &Resolver{fs: realFS, log: log, options: options}
+}
+
+// Result is what Resolve returns for a successfully-resolved import.
+type Result struct {
+	AbsPath   string
+	Namespace string
+	External  bool
+}
+
+// Resolve turns importPath (as written in importer, or as an entry point
+// when importer is empty) into a Result. importerNamespace is the
+// namespace importer itself was loaded under ("" for the default file
+// namespace) and is passed straight through to a plugin's OnResolve hook as
+// Namespace, so a plugin registered against a specific namespace (e.g. a
+// virtual module's own re-exports) can keep resolving imports within that
+// namespace. Plugin resolve hooks are tried, in registration order, before
+// any of esbuild's own path math; the first plugin whose hook claims the
+// path wins.
+func (r *Resolver) Resolve(importPath string, importer string, importerNamespace string, resolveDir string) (Result, bool) {
+	if r.options.ExternalModules[importPath] {
+		return Result{AbsPath: importPath, External: true}, true
+	}
+
+	for _, plugin := range r.options.Plugins {
+		if plugin.OnResolve == nil {
+			continue
+		}
+		if result, ok := plugin.OnResolve(OnResolveArgs{
+			Path:       importPath,
+			Importer:   importer,
+			Namespace:  importerNamespace,
+			ResolveDir: resolveDir,
+		}); ok {
+			return Result{AbsPath: result.Path, Namespace: result.Namespace, External: result.External}, true
+		}
+	}
+
+	if IsNonModulePath(importPath) {
+		return r.resolvePath(r.fs.Join(resolveDir, importPath))
+	}
+
+	// Bare specifier: try tsconfig "paths" against "baseUrl" first.
+	if candidate, ok := r.resolveTsconfigPaths(importPath); ok {
+		return r.resolvePath(candidate)
+	}
+
+	// Fall back to resolving it relative to baseUrl, if any, otherwise as a
+	// plain relative path (this module doesn't implement node_modules
+	// traversal; that's entirely outside the scope of what's been asked for
+	// so far).
+	if r.options.BaseURL != "" {
+		return r.resolvePath(r.fs.Join(r.options.BaseURL, importPath))
+	}
+	return r.resolvePath(r.fs.Join(resolveDir, importPath))
+}
+
+// resolveTsconfigPaths matches importPath against tsconfig.json's
+// "compilerOptions.paths" patterns (each of which may have one trailing
+// "*" wildcard), returning the first substitution resolved against
+// BaseURL. Mappings are tried in the order tsconfig.json declared them, so
+// a more specific pattern can be listed ahead of a catch-all "*" and win.
+func (r *Resolver) resolveTsconfigPaths(importPath string) (string, bool) {
+	for _, mapping := range r.options.Paths {
+		if len(mapping.Substitutions) == 0 {
+			continue
+		}
+		if mapping.Pattern == importPath {
+			return r.fs.Join(r.options.BaseURL, mapping.Substitutions[0]), true
+		}
+		if prefix := strings.TrimSuffix(mapping.Pattern, "*"); strings.HasSuffix(mapping.Pattern, "*") && strings.HasPrefix(importPath, prefix) {
+			suffix := importPath[len(prefix):]
+			target := strings.Replace(mapping.Substitutions[0], "*", suffix, 1)
+			return r.fs.Join(r.options.BaseURL, target), true
+		}
+	}
+	return "", false
+}
+
+// resolvePath tries absPath as-is, then with each extension in
+// ExtensionOrder appended, returning the first one that exists on disk or
+// among VirtualPaths.
+func (r *Resolver) resolvePath(absPath string) (Result, bool) {
+	if r.exists(absPath) {
+		return Result{AbsPath: absPath}, true
+	}
+	for _, ext := range r.options.ExtensionOrder {
+		if r.exists(absPath + ext) {
+			return Result{AbsPath: absPath + ext}, true
+		}
+	}
+	return Result{}, false
+}
+
+func (r *Resolver) exists(absPath string) bool {
+	if r.options.VirtualPaths[absPath] {
+		return true
+	}
+	_, ok := r.fs.ReadFile(absPath)
+	return ok
+}
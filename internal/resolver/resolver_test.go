@@ -0,0 +1,77 @@
+package resolver
+
+import (
+	"testing"
+
+	"github.com/evanw/esbuild/internal/fs"
+	"github.com/evanw/esbuild/internal/logging"
+)
+
+func TestResolveTsconfigPathsOrderWins(t *testing.T) {
+	mockFS := fs.MockFS(map[string]string{
+		"/project/src/utils/helper.js": "",
+		"/project/src/fallback.js":     "",
+	})
+	res := NewResolver(mockFS, logging.NewDeferLog(), ResolveOptions{
+		BaseURL:        "/project/src",
+		ExtensionOrder: []string{".js"},
+		Paths: []PathMapping{
+			{Pattern: "utils/*", Substitutions: []string{"utils/*"}},
+			{Pattern: "*", Substitutions: []string{"fallback"}},
+		},
+	})
+
+	result, ok := res.Resolve("utils/helper", "", "", "/project")
+	if !ok {
+		t.Fatalf("expected resolve to succeed")
+	}
+	if result.AbsPath != "/project/src/utils/helper.js" {
+		t.Fatalf("expected the more specific \"utils/*\" pattern to win, got %q", result.AbsPath)
+	}
+}
+
+func TestResolveTsconfigPathsCatchAllFallsThrough(t *testing.T) {
+	mockFS := fs.MockFS(map[string]string{
+		"/project/src/fallback.js": "",
+	})
+	res := NewResolver(mockFS, logging.NewDeferLog(), ResolveOptions{
+		BaseURL:        "/project/src",
+		ExtensionOrder: []string{".js"},
+		Paths: []PathMapping{
+			{Pattern: "utils/*", Substitutions: []string{"utils/*"}},
+			{Pattern: "*", Substitutions: []string{"fallback"}},
+		},
+	})
+
+	result, ok := res.Resolve("anything", "", "", "/project")
+	if !ok {
+		t.Fatalf("expected resolve to succeed")
+	}
+	if result.AbsPath != "/project/src/fallback.js" {
+		t.Fatalf("expected the catch-all pattern to match, got %q", result.AbsPath)
+	}
+}
+
+func TestResolveForwardsImporterNamespaceToPlugins(t *testing.T) {
+	var gotNamespace string
+	res := NewResolver(fs.MockFS(nil), logging.NewDeferLog(), ResolveOptions{
+		Plugins: []Plugin{{
+			Name: "test",
+			OnResolve: func(args OnResolveArgs) (OnResolveResult, bool) {
+				gotNamespace = args.Namespace
+				return OnResolveResult{Path: "/resolved", Namespace: args.Namespace}, true
+			},
+		}},
+	})
+
+	result, ok := res.Resolve("./shim", "/virtual/entry", "my-ns", "/virtual")
+	if !ok {
+		t.Fatalf("expected resolve to succeed")
+	}
+	if gotNamespace != "my-ns" {
+		t.Fatalf("expected OnResolve to see importer namespace %q, got %q", "my-ns", gotNamespace)
+	}
+	if result.Namespace != "my-ns" {
+		t.Fatalf("expected result namespace %q, got %q", "my-ns", result.Namespace)
+	}
+}
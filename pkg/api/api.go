@@ -0,0 +1,372 @@
+// This file contains the public-facing API types for the "api" package.
+// See "api_impl.go" for the code that implements this API.
+
+package api
+
+import (
+	"encoding/json"
+	"regexp"
+)
+
+type Platform uint8
+
+const (
+	PlatformBrowser Platform = iota
+	PlatformNode
+)
+
+type Format uint8
+
+const (
+	FormatDefault Format = iota
+	FormatIIFE
+	FormatCommonJS
+	FormatESModule
+)
+
+type SourceMap uint8
+
+const (
+	SourceMapNone SourceMap = iota
+	SourceMapLinked
+	SourceMapInline
+	SourceMapExternal
+)
+
+type StderrColor uint8
+
+const (
+	ColorIfTerminal StderrColor = iota
+	ColorNever
+	ColorAlways
+)
+
+type LogLevel uint8
+
+const (
+	LogLevelInfo LogLevel = iota
+	LogLevelWarning
+	LogLevelError
+	LogLevelSilent
+)
+
+type Target uint8
+
+const (
+	ESNext Target = iota
+	ES2015
+	ES2016
+	ES2017
+	ES2018
+	ES2019
+	ES2020
+)
+
+type Loader uint8
+
+const (
+	LoaderNone Loader = iota
+	LoaderJS
+	LoaderJSX
+	LoaderTS
+	LoaderTSX
+	LoaderJSON
+	LoaderText
+	LoaderBase64
+	LoaderDataURL
+	LoaderFile
+)
+
+type StrictOptions struct {
+	NullishCoalescing bool
+	ClassFields       bool
+}
+
+type Message struct {
+	Text     string
+	Location *Location
+}
+
+type Location struct {
+	File     string
+	Line     int // 1-based
+	Column   int // 0-based, in bytes
+	Length   int // in bytes
+	LineText string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Build API
+
+type BuildOptions struct {
+	Target   Target
+	Strict   StrictOptions
+	Platform Platform
+
+	MinifyWhitespace  bool
+	MinifyIdentifiers bool
+	MinifySyntax      bool
+
+	JSXFactory  string
+	JSXFragment string
+
+	Defines map[string]string
+
+	Sourcemap SourceMap
+
+	Bundle     bool
+	Splitting  bool
+	Format     Format
+	GlobalName string
+
+	Outfile string
+	Outdir  string
+
+	Metafile string
+
+	// NeedsMetafile requests that BuildResult.Metafile be populated even
+	// when Metafile above (an output path) is left unset.
+	NeedsMetafile bool
+
+	EntryPoints []string
+
+	Loaders           map[string]Loader
+	ResolveExtensions []string
+	Externals         []string
+
+	Plugins []Plugin
+
+	// VirtualFiles serves the given contents for an entry point or import
+	// path instead of reading it from the file system, keyed by that path.
+	// Relative imports from a virtual file resolve against its ResolveDir.
+	VirtualFiles map[string]VirtualFile
+
+	// Tsconfig is the path to a tsconfig.json file to apply. If empty,
+	// esbuild walks up from each entry point looking for one. Fields set
+	// directly on BuildOptions take precedence over the tsconfig, except
+	// Target: ESNext is its zero value and can't be told apart from unset,
+	// so a tsconfig "target" overrides an explicit ESNext.
+	Tsconfig string
+
+	// Incremental keeps the validated options and parsed ASTs around after
+	// the build so that BuildResult.Rebuild can skip reparsing files whose
+	// contents haven't changed. Every file is still read and its imports
+	// resolved again on each rebuild.
+	Incremental bool
+
+	// Watch keeps the build running in the background, re-running it and
+	// invoking OnRebuild whenever a file the build depended on changes.
+	Watch *WatchOptions
+
+	ErrorLimit int
+	LogLevel   LogLevel
+	Color      StderrColor
+}
+
+type BuildResult struct {
+	Errors   []Message
+	Warnings []Message
+
+	OutputFiles []OutputFile
+
+	// Metafile is a JSON string describing the inputs and outputs of the
+	// build, populated whenever BuildOptions.Metafile or NeedsMetafile is
+	// set. Unmarshal it into MetafileData for a typed view, or write it
+	// straight to disk if that's all the caller wants.
+	Metafile string
+
+	// Rebuild and Dispose are non-nil when BuildOptions.Incremental is true
+	// and the options passed validation (a validation error returns a result
+	// with only Errors and Warnings set). Rebuild re-runs the build, reusing
+	// any cached ASTs from the previous build for files that haven't
+	// changed. Dispose frees the AST cache once no more rebuilds are needed.
+	Rebuild func() BuildResult
+	Dispose func()
+
+	// Stop is non-nil when BuildOptions.Watch is set and the options passed
+	// validation. Calling it tears down the background watcher; no more
+	// OnRebuild calls happen afterward.
+	Stop func()
+}
+
+// WatchOptions enables watch mode. OnRebuild is called with the result of
+// every rebuild triggered by a change to a file the build depends on,
+// including the entry points, every resolved import, and tsconfig.json.
+type WatchOptions struct {
+	OnRebuild func(BuildResult)
+}
+
+type OutputFile struct {
+	Path     string
+	Contents []byte
+}
+
+func Build(options BuildOptions) BuildResult {
+	return buildImpl(options)
+}
+
+// VirtualFile is the in-memory counterpart to a file on disk. It lets a
+// caller treat esbuild as a pure function over a map of paths to contents,
+// without needing MockFS or temporary files on disk.
+type VirtualFile struct {
+	Contents   string
+	Loader     Loader
+	ResolveDir string
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Metafile
+
+// MetafileData is a typed view over BuildResult.Metafile, for callers that
+// want to render a dependency graph or compute chunk sizes without hand-
+// parsing the JSON themselves.
+type MetafileData struct {
+	Inputs  map[string]MetafileInput  `json:"inputs"`
+	Outputs map[string]MetafileOutput `json:"outputs"`
+}
+
+type MetafileInput struct {
+	Bytes   int              `json:"bytes"`
+	Imports []MetafileImport `json:"imports"`
+}
+
+type MetafileImport struct {
+	Path string `json:"path"`
+}
+
+type MetafileOutputInput struct {
+	BytesInOutput int `json:"bytesInOutput"`
+}
+
+type MetafileOutput struct {
+	Bytes      int                            `json:"bytes"`
+	Inputs     map[string]MetafileOutputInput `json:"inputs"`
+	Imports    []MetafileImport               `json:"imports"`
+	Exports    []string                       `json:"exports"`
+	EntryPoint string                         `json:"entryPoint,omitempty"`
+}
+
+// ParseMetafile unmarshals a BuildResult.Metafile JSON string into a typed
+// MetafileData.
+func ParseMetafile(metafile string) (MetafileData, error) {
+	var data MetafileData
+	err := json.Unmarshal([]byte(metafile), &data)
+	return data, err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Transform API
+
+type TransformOptions struct {
+	Target Target
+	Strict StrictOptions
+
+	MinifyWhitespace  bool
+	MinifyIdentifiers bool
+	MinifySyntax      bool
+
+	JSXFactory  string
+	JSXFragment string
+
+	Defines map[string]string
+
+	Sourcemap  SourceMap
+	Sourcefile string
+	Loader     Loader
+
+	ErrorLimit int
+	LogLevel   LogLevel
+	Color      StderrColor
+}
+
+type TransformResult struct {
+	Errors   []Message
+	Warnings []Message
+
+	JS          []byte
+	JSSourceMap []byte
+}
+
+func Transform(input string, options TransformOptions) TransformResult {
+	return transformImpl(input, options)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Plugin API
+
+// ResolveArgs is passed to an OnResolve callback with the import path being
+// resolved and the context it was imported from.
+type ResolveArgs struct {
+	Path       string
+	Importer   string
+	Namespace  string
+	ResolveDir string
+}
+
+// ResolveResult is returned from an OnResolve callback. A non-empty
+// Namespace marks Path as a virtual module that the plugin will later serve
+// contents for via OnLoad; with an empty Namespace, Path is an absolute
+// file path that is loaded like any other file.
+type ResolveResult struct {
+	Path      string
+	Namespace string
+	External  bool
+}
+
+// LoadArgs is passed to an OnLoad callback with the module being loaded:
+// either a file path (empty Namespace) or a virtual module that a prior
+// OnResolve call produced.
+type LoadArgs struct {
+	Path      string
+	Namespace string
+}
+
+// LoadResult is returned from an OnLoad callback to supply the contents of
+// a virtual module in place of reading it from the file system.
+type LoadResult struct {
+	Contents   string
+	Loader     Loader
+	ResolveDir string
+}
+
+type ResolveFunc func(ResolveArgs) (ResolveResult, error)
+type LoadFunc func(LoadArgs) (LoadResult, error)
+
+// Plugin lets a caller of Build extend the resolver and loader with custom
+// logic. A plugin may register any number of OnResolve and OnLoad callbacks;
+// each is tried in registration order until one matches the given filter
+// and namespace.
+type Plugin struct {
+	Name string
+
+	onResolve []pluginOnResolve
+	onLoad    []pluginOnLoad
+}
+
+type pluginOnResolve struct {
+	filter    *regexp.Regexp
+	namespace string
+	fn        ResolveFunc
+}
+
+type pluginOnLoad struct {
+	filter    *regexp.Regexp
+	namespace string
+	fn        LoadFunc
+}
+
+// OnResolve registers a callback that's consulted before esbuild's default
+// file system resolution. A non-empty namespace restricts this callback to
+// imports from that namespace; "" matches imports from every namespace.
+func (p *Plugin) OnResolve(filter *regexp.Regexp, namespace string, fn ResolveFunc) {
+	p.onResolve = append(p.onResolve, pluginOnResolve{filter, namespace, fn})
+}
+
+// OnLoad registers a callback that's consulted before esbuild reads a file
+// off of the file system. A non-empty namespace restricts this callback to
+// modules loaded under that namespace (such as virtual modules produced by
+// a matching OnResolve callback); "" matches every module, including files
+// on disk.
+func (p *Plugin) OnLoad(filter *regexp.Regexp, namespace string, fn LoadFunc) {
+	p.onLoad = append(p.onLoad, pluginOnLoad{filter, namespace, fn})
+}
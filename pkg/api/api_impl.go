@@ -3,6 +3,10 @@ package api
 import (
 	"fmt"
 	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
 
 	"github.com/evanw/esbuild/internal/ast"
 	"github.com/evanw/esbuild/internal/bundler"
@@ -134,6 +138,19 @@ func validateLoader(value Loader) bundler.Loader {
 	}
 }
 
+// validateLoaderOrNone is like validateLoader but also accepts the zero
+// value LoaderNone, which means "detect the loader from the file
+// extension" rather than a caller error - the only two callers that can
+// see an unset Loader (a VirtualFile or an OnLoad result the caller didn't
+// bother to set) need that distinction instead of hitting validateLoader's
+// panic.
+func validateLoaderOrNone(value Loader) bundler.Loader {
+	if value == LoaderNone {
+		return bundler.LoaderNone
+	}
+	return validateLoader(value)
+}
+
 func validateExternals(log logging.Log, paths []string) map[string]bool {
 	result := make(map[string]bool)
 	for _, path := range paths {
@@ -244,6 +261,335 @@ func validateDefines(log logging.Log, defines map[string]string) *parser.Process
 	return &processed
 }
 
+// validateResolvePlugins adapts each Plugin's registered OnResolve callbacks
+// into resolver.Plugin values. The resolver consults these before ever
+// touching the file system.
+func validateResolvePlugins(plugins []Plugin) []resolver.Plugin {
+	if len(plugins) == 0 {
+		return nil
+	}
+	result := make([]resolver.Plugin, len(plugins))
+	for i, plugin := range plugins {
+		plugin := plugin
+		result[i] = resolver.Plugin{
+			Name: plugin.Name,
+			OnResolve: func(args resolver.OnResolveArgs) (resolver.OnResolveResult, bool) {
+				for _, onResolve := range plugin.onResolve {
+					if onResolve.namespace != "" && onResolve.namespace != args.Namespace {
+						continue
+					}
+					if !onResolve.filter.MatchString(args.Path) {
+						continue
+					}
+					result, err := onResolve.fn(ResolveArgs{
+						Path:       args.Path,
+						Importer:   args.Importer,
+						Namespace:  args.Namespace,
+						ResolveDir: args.ResolveDir,
+					})
+					if err != nil {
+						return resolver.OnResolveResult{}, false
+					}
+					return resolver.OnResolveResult{
+						Path:      result.Path,
+						Namespace: result.Namespace,
+						External:  result.External,
+					}, true
+				}
+				return resolver.OnResolveResult{}, false
+			},
+		}
+	}
+	return result
+}
+
+// validateLoadPlugins adapts each Plugin's registered OnLoad callbacks into
+// bundler.LoadPlugin values. Only the bundler ever reads file contents, so
+// the load phase lives there rather than in the resolver.
+func validateLoadPlugins(plugins []Plugin) []bundler.LoadPlugin {
+	if len(plugins) == 0 {
+		return nil
+	}
+	result := make([]bundler.LoadPlugin, len(plugins))
+	for i, plugin := range plugins {
+		plugin := plugin
+		result[i] = bundler.LoadPlugin{
+			Name: plugin.Name,
+			OnLoad: func(args bundler.OnLoadArgs) (bundler.OnLoadResult, bool) {
+				for _, onLoad := range plugin.onLoad {
+					if onLoad.namespace != "" && onLoad.namespace != args.Namespace {
+						continue
+					}
+					if !onLoad.filter.MatchString(args.Path) {
+						continue
+					}
+					result, err := onLoad.fn(LoadArgs{
+						Path:      args.Path,
+						Namespace: args.Namespace,
+					})
+					if err != nil {
+						return bundler.OnLoadResult{}, false
+					}
+					return bundler.OnLoadResult{
+						Contents:   result.Contents,
+						Loader:     validateLoaderOrNone(result.Loader),
+						ResolveDir: result.ResolveDir,
+					}, true
+				}
+				return bundler.OnLoadResult{}, false
+			},
+		}
+	}
+	return result
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// tsconfig.json
+
+// tsconfigOptions holds the subset of "compilerOptions" that influence how
+// esbuild parses and resolves TypeScript. Fields are left at their zero
+// value when absent from the file, so an absent field and an explicit
+// false or empty value look the same; applyTsconfig only applies values
+// that are non-zero.
+type tsconfigOptions struct {
+	baseURL                 string
+	paths                   []resolver.PathMapping
+	jsxFactory              []string
+	jsxFragment             []string
+	target                  string
+	useDefineForClassFields bool
+	importsNotUsedAsValues  string
+	experimentalDecorators  bool
+}
+
+// findTsconfigPath walks up from the directory of each entry point looking
+// for a tsconfig.json, stopping at the first one found.
+func findTsconfigPath(fs fs.FS, entryPaths []string) string {
+	for _, entry := range entryPaths {
+		dir := fs.Dir(entry)
+		for {
+			candidate := fs.Join(dir, "tsconfig.json")
+			if _, ok := fs.ReadFile(candidate); ok {
+				return candidate
+			}
+			parent := fs.Dir(dir)
+			if parent == dir {
+				break
+			}
+			dir = parent
+		}
+	}
+	return ""
+}
+
+func tsconfigProperty(obj *ast.EObject, name string) (ast.E, bool) {
+	for _, prop := range obj.Properties {
+		if key, ok := prop.Key.Data.(*ast.EString); ok && prop.Value != nil && lexer.UTF16ToString(key.Value) == name {
+			return prop.Value.Data, true
+		}
+	}
+	return nil, false
+}
+
+func parseTsconfig(log logging.Log, fs fs.FS, path string) *tsconfigOptions {
+	contents, ok := fs.ReadFile(path)
+	if !ok {
+		log.AddError(nil, ast.Loc{}, fmt.Sprintf("Cannot read file: %s", path))
+		return nil
+	}
+
+	// tsconfig.json allows comments and trailing commas, unlike strict JSON
+	source := logging.Source{Contents: contents, PrettyPath: path}
+	root, ok := parser.ParseJSON(log, source, parser.ParseJSONOptions{AllowComments: true, AllowTrailingCommas: true})
+	if !ok {
+		return nil
+	}
+	rootObj, ok := root.Data.(*ast.EObject)
+	if !ok {
+		return &tsconfigOptions{}
+	}
+	result := &tsconfigOptions{}
+	compilerOptionsData, ok := tsconfigProperty(rootObj, "compilerOptions")
+	if !ok {
+		return result
+	}
+	compilerOptions, ok := compilerOptionsData.(*ast.EObject)
+	if !ok {
+		return result
+	}
+
+	if value, ok := tsconfigProperty(compilerOptions, "baseUrl"); ok {
+		if str, ok := value.(*ast.EString); ok {
+			result.baseURL = lexer.UTF16ToString(str.Value)
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "jsxFactory"); ok {
+		if str, ok := value.(*ast.EString); ok {
+			result.jsxFactory = strings.Split(lexer.UTF16ToString(str.Value), ".")
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "jsxFragmentFactory"); ok {
+		if str, ok := value.(*ast.EString); ok {
+			result.jsxFragment = strings.Split(lexer.UTF16ToString(str.Value), ".")
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "target"); ok {
+		if str, ok := value.(*ast.EString); ok {
+			result.target = lexer.UTF16ToString(str.Value)
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "useDefineForClassFields"); ok {
+		if b, ok := value.(*ast.EBoolean); ok {
+			result.useDefineForClassFields = b.Value
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "importsNotUsedAsValues"); ok {
+		if str, ok := value.(*ast.EString); ok {
+			result.importsNotUsedAsValues = lexer.UTF16ToString(str.Value)
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "experimentalDecorators"); ok {
+		if b, ok := value.(*ast.EBoolean); ok {
+			result.experimentalDecorators = b.Value
+		}
+	}
+	if value, ok := tsconfigProperty(compilerOptions, "paths"); ok {
+		if obj, ok := value.(*ast.EObject); ok {
+			// obj.Properties is already in declaration order, which matters:
+			// tsconfig.json path mappings are tried in the order they're
+			// written, so a more specific pattern can be listed ahead of a
+			// catch-all "*" and win.
+			for _, prop := range obj.Properties {
+				key, ok := prop.Key.Data.(*ast.EString)
+				arr, arrOk := prop.Value.Data.(*ast.EArray)
+				if !ok || !arrOk {
+					continue
+				}
+				var list []string
+				for _, item := range arr.Items {
+					if str, ok := item.Data.(*ast.EString); ok {
+						list = append(list, lexer.UTF16ToString(str.Value))
+					}
+				}
+				result.paths = append(result.paths, resolver.PathMapping{
+					Pattern:       lexer.UTF16ToString(key.Value),
+					Substitutions: list,
+				})
+			}
+		}
+	}
+
+	return result
+}
+
+// tsconfigTargets maps the "target" strings tsconfig.json accepts onto our
+// own LanguageTarget enum. Anything tsc supports that we don't have a
+// direct equivalent for (e.g. "es5") is left unmapped and ignored.
+var tsconfigTargets = map[string]parser.LanguageTarget{
+	"es2015": parser.ES2015,
+	"es2016": parser.ES2016,
+	"es2017": parser.ES2017,
+	"es2018": parser.ES2018,
+	"es2019": parser.ES2019,
+	"es2020": parser.ES2020,
+	"esnext": parser.ESNext,
+}
+
+// applyTsconfig merges a discovered (or explicitly specified) tsconfig.json
+// into the parse and resolve options, returning its absolute path (or "" if
+// none was found or specified) so the caller can track it for watch mode.
+// Fields the caller set explicitly on BuildOptions always win over whatever
+// the tsconfig says.
+func applyTsconfig(
+	log logging.Log,
+	realFS fs.FS,
+	options BuildOptions,
+	entryPaths []string,
+	parseOptions *parser.ParseOptions,
+	resolveOptions *resolver.ResolveOptions,
+) string {
+	path := options.Tsconfig
+	if path != "" {
+		path = validatePath(log, realFS, path)
+	} else {
+		path = findTsconfigPath(realFS, entryPaths)
+		if path == "" {
+			return ""
+		}
+	}
+
+	tsconfig := parseTsconfig(log, realFS, path)
+	if tsconfig == nil {
+		return path
+	}
+
+	if tsconfig.baseURL != "" {
+		resolveOptions.BaseURL = validatePath(log, realFS, tsconfig.baseURL)
+	}
+	if tsconfig.paths != nil {
+		resolveOptions.Paths = tsconfig.paths
+	}
+	if options.JSXFactory == "" && tsconfig.jsxFactory != nil {
+		parseOptions.JSX.Factory = tsconfig.jsxFactory
+	}
+	if options.JSXFragment == "" && tsconfig.jsxFragment != nil {
+		parseOptions.JSX.Fragment = tsconfig.jsxFragment
+	}
+	// Target's zero value (ESNext) is indistinguishable from "the caller
+	// didn't set it", so we treat ESNext as "unset" and let tsconfig win.
+	// This means an explicit ESNext on BuildOptions is overridden by any
+	// "target" the tsconfig sets.
+	if options.Target == ESNext {
+		if target, ok := tsconfigTargets[strings.ToLower(tsconfig.target)]; ok {
+			parseOptions.Target = target
+		}
+	}
+	if tsconfig.useDefineForClassFields {
+		parseOptions.UseDefineForClassFields = true
+	}
+	if tsconfig.experimentalDecorators {
+		parseOptions.TS.ExperimentalDecorators = true
+	}
+	if tsconfig.importsNotUsedAsValues != "" {
+		parseOptions.TS.ImportsNotUsedAsValues = tsconfig.importsNotUsedAsValues
+	}
+	return path
+}
+
+func validateVirtualFiles(log logging.Log, realFS fs.FS, files map[string]VirtualFile) map[string]bundler.VirtualFile {
+	if len(files) == 0 {
+		return nil
+	}
+	result := make(map[string]bundler.VirtualFile, len(files))
+	for path, file := range files {
+		absPath := validatePath(log, realFS, path)
+		resolveDir := file.ResolveDir
+		if resolveDir != "" {
+			resolveDir = validatePath(log, realFS, resolveDir)
+		}
+		result[absPath] = bundler.VirtualFile{
+			Contents:   file.Contents,
+			Loader:     validateLoaderOrNone(file.Loader),
+			ResolveDir: resolveDir,
+		}
+	}
+	return result
+}
+
+// virtualFilePaths returns the absolute paths of every virtual file, so the
+// resolver can tell a relative import that lands on one of them apart from
+// a real file that just doesn't exist on disk.
+func virtualFilePaths(files map[string]bundler.VirtualFile) map[string]bool {
+	if len(files) == 0 {
+		return nil
+	}
+	result := make(map[string]bool, len(files))
+	for absPath := range files {
+		result[absPath] = true
+	}
+	return result
+}
+
 func validatePath(log logging.Log, fs fs.FS, relPath string) string {
 	if relPath == "" {
 		return ""
@@ -297,6 +643,200 @@ func messagesOfKind(kind logging.MsgKind, msgs []logging.Msg) []Message {
 ////////////////////////////////////////////////////////////////////////////////
 // Build API
 
+// buildContext retains everything a subsequent Rebuild() needs to avoid
+// redoing work that a fresh Build() would otherwise repeat: the validated
+// resolve, parse, and bundle options, and the ASTs parsed on the previous
+// run, keyed by absolute path + content hash + parse options so a changed
+// file can never return a stale cache hit. The resolver itself holds no
+// state beyond its options and the per-build log, so each rebuild creates
+// a new one from resolveOptions.
+type buildContext struct {
+	realFS         fs.FS
+	resolveOptions resolver.ResolveOptions
+	parseOptions   parser.ParseOptions
+	bundleOptions  bundler.BundleOptions
+	entryPaths     []string
+	tsconfigPath   string
+	options        BuildOptions
+
+	mutex    sync.Mutex
+	astCache map[bundler.ASTCacheKey]*ast.AST
+
+	// watchPaths is every absolute path the most recent build read from
+	// disk: entry points, resolved imports, and tsconfig.json. It's read by
+	// the watch goroutine and written by rebuild(), both of which can run
+	// concurrently (a caller may set both Incremental and Watch), so it's
+	// guarded by the same mutex as astCache.
+	watchPaths []string
+}
+
+// getWatchPaths returns a snapshot of the most recent build's watchPaths.
+func (ctx *buildContext) getWatchPaths() []string {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+	return append([]string(nil), ctx.watchPaths...)
+}
+
+func (ctx *buildContext) rebuild() BuildResult {
+	var log logging.Log
+	if ctx.options.LogLevel == LogLevelSilent {
+		log = logging.NewDeferLog()
+	} else {
+		log = logging.NewStderrLog(logging.StderrOptions{
+			IncludeSource: true,
+			ErrorLimit:    ctx.options.ErrorLimit,
+			Color:         validateColor(ctx.options.Color),
+			LogLevel:      validateLogLevel(ctx.options.LogLevel),
+		})
+	}
+
+	var outputFiles []OutputFile
+
+	// Create a resolver from the retained options and let the bundler consult
+	// (and populate) the shared AST cache instead of reparsing everything
+	res := resolver.NewResolver(ctx.realFS, log, ctx.resolveOptions)
+	bundleOptions := ctx.bundleOptions
+	bundleOptions.ASTCache = &bundler.ASTCache{
+		Get: func(key bundler.ASTCacheKey) (*ast.AST, bool) {
+			ctx.mutex.Lock()
+			defer ctx.mutex.Unlock()
+			cached, ok := ctx.astCache[key]
+			return cached, ok
+		},
+		Put: func(key bundler.ASTCacheKey, value *ast.AST) {
+			ctx.mutex.Lock()
+			defer ctx.mutex.Unlock()
+			ctx.astCache[key] = value
+		},
+	}
+
+	bundle := bundler.ScanBundle(log, ctx.realFS, res, ctx.entryPaths, ctx.parseOptions, bundleOptions)
+	watchPaths := bundle.WatchPaths()
+	if ctx.tsconfigPath != "" {
+		watchPaths = append(watchPaths, ctx.tsconfigPath)
+	}
+	ctx.mutex.Lock()
+	ctx.watchPaths = watchPaths
+	ctx.mutex.Unlock()
+	var metafileJSON string
+	if !log.HasErrors() {
+		results, metafile := bundle.Compile(log, bundleOptions)
+		metafileJSON = metafile
+		outputFiles = make([]OutputFile, len(results))
+		for i, result := range results {
+			outputFiles[i] = OutputFile{
+				Path:     result.AbsPath,
+				Contents: result.Contents,
+			}
+		}
+	}
+
+	msgs := log.Done()
+	result := BuildResult{
+		Errors:      messagesOfKind(logging.Error, msgs),
+		Warnings:    messagesOfKind(logging.Warning, msgs),
+		OutputFiles: outputFiles,
+		Metafile:    metafileJSON,
+	}
+	if ctx.options.Incremental {
+		result.Rebuild = ctx.rebuild
+		result.Dispose = ctx.dispose
+	}
+	return result
+}
+
+func (ctx *buildContext) dispose() {
+	ctx.mutex.Lock()
+	defer ctx.mutex.Unlock()
+	ctx.astCache = make(map[bundler.ASTCacheKey]*ast.AST)
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// Watch mode
+
+// watchDebounce coalesces the burst of events a single save can generate
+// (most editors write, then chmod, then rename-over) into one rebuild.
+const watchDebounce = 20 * time.Millisecond
+
+// watch uses fsnotify to watch every path in ctx.watchPaths and calls
+// onRebuild with the result of ctx.rebuild whenever one of them changes.
+// The watch set is re-synced against the new watchPaths after every
+// rebuild, since which files are read can itself change (a new import
+// added, an old one removed).
+func (ctx *buildContext) watch(onRebuild func(BuildResult)) func() {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		// Nothing sensible to do with a watcher we can't create; return a
+		// no-op Stop so the caller's build result is still usable without it.
+		return func() {}
+	}
+
+	watched := make(map[string]bool)
+	syncWatched := func(paths []string) {
+		next := make(map[string]bool, len(paths))
+		for _, path := range paths {
+			next[path] = true
+			if !watched[path] {
+				watcher.Add(path)
+			}
+		}
+		for path := range watched {
+			if !next[path] {
+				watcher.Remove(path)
+			}
+		}
+		watched = next
+	}
+	syncWatched(ctx.getWatchPaths())
+
+	done := make(chan struct{})
+	go func() {
+		defer watcher.Close()
+		var debounce *time.Timer
+		for {
+			select {
+			case <-done:
+				if debounce != nil {
+					debounce.Stop()
+				}
+				return
+			case _, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if debounce == nil {
+					debounce = time.NewTimer(watchDebounce)
+				} else {
+					debounce.Reset(watchDebounce)
+				}
+			case _, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+			case <-debounceC(debounce):
+				debounce = nil
+				onRebuild(ctx.rebuild())
+				syncWatched(ctx.getWatchPaths())
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
+
+// debounceC returns t.C, or a nil channel (which blocks forever) when t is
+// nil - letting the watch loop's select skip the debounce case until a
+// timer has actually been armed by an event.
+func debounceC(t *time.Timer) <-chan time.Time {
+	if t == nil {
+		return nil
+	}
+	return t.C
+}
+
 func buildImpl(options BuildOptions) BuildResult {
 	var log logging.Log
 	if options.LogLevel == LogLevelSilent {
@@ -336,18 +876,25 @@ func buildImpl(options BuildOptions) BuildResult {
 		AbsOutputFile:     validatePath(log, realFS, options.Outfile),
 		AbsOutputDir:      validatePath(log, realFS, options.Outdir),
 		AbsMetadataFile:   validatePath(log, realFS, options.Metafile),
+		NeedsMetafile:     options.Metafile != "" || options.NeedsMetafile,
 		ExtensionToLoader: validateLoaders(log, options.Loaders),
+		VirtualFiles:      validateVirtualFiles(log, realFS, options.VirtualFiles),
+		Plugins:           validateLoadPlugins(options.Plugins),
 	}
 	resolveOptions := resolver.ResolveOptions{
 		Platform:        validatePlatform(options.Platform),
 		ExtensionOrder:  validateResolveExtensions(log, options.ResolveExtensions),
 		ExternalModules: validateExternals(log, options.Externals),
+		Plugins:         validateResolvePlugins(options.Plugins),
+		VirtualPaths:    virtualFilePaths(bundleOptions.VirtualFiles),
 	}
 	entryPaths := make([]string, len(options.EntryPoints))
 	for i, entryPoint := range options.EntryPoints {
 		entryPaths[i] = validatePath(log, realFS, entryPoint)
 	}
 
+	tsconfigPath := applyTsconfig(log, realFS, options, entryPaths, &parseOptions, &resolveOptions)
+
 	if bundleOptions.AbsOutputDir == "" && len(entryPaths) > 1 {
 		log.AddError(nil, ast.Loc{},
 			"Must use \"outdir\" when there are multiple input files")
@@ -398,36 +945,30 @@ func buildImpl(options BuildOptions) BuildResult {
 		log.AddError(nil, ast.Loc{}, "Spltting currently only works with the \"esm\" format")
 	}
 
-	var outputFiles []OutputFile
-
 	// Stop now if there were errors
-	if !log.HasErrors() {
-		// Scan over the bundle
-		resolver := resolver.NewResolver(realFS, log, resolveOptions)
-		bundle := bundler.ScanBundle(log, realFS, resolver, entryPaths, parseOptions, bundleOptions)
-
-		// Stop now if there were errors
-		if !log.HasErrors() {
-			// Compile the bundle
-			results := bundle.Compile(log, bundleOptions)
-
-			// Return the results
-			outputFiles = make([]OutputFile, len(results))
-			for i, result := range results {
-				outputFiles[i] = OutputFile{
-					Path:     result.AbsPath,
-					Contents: result.Contents,
-				}
-			}
+	if log.HasErrors() {
+		msgs := log.Done()
+		return BuildResult{
+			Errors:   messagesOfKind(logging.Error, msgs),
+			Warnings: messagesOfKind(logging.Warning, msgs),
 		}
 	}
 
-	msgs := log.Done()
-	return BuildResult{
-		Errors:      messagesOfKind(logging.Error, msgs),
-		Warnings:    messagesOfKind(logging.Warning, msgs),
-		OutputFiles: outputFiles,
+	ctx := &buildContext{
+		realFS:         realFS,
+		resolveOptions: resolveOptions,
+		parseOptions:   parseOptions,
+		bundleOptions:  bundleOptions,
+		entryPaths:     entryPaths,
+		tsconfigPath:   tsconfigPath,
+		options:        options,
+		astCache:       make(map[bundler.ASTCacheKey]*ast.AST),
 	}
+	result := ctx.rebuild()
+	if options.Watch != nil {
+		result.Stop = ctx.watch(options.Watch.OnRebuild)
+	}
+	return result
 }
 
 ////////////////////////////////////////////////////////////////////////////////
@@ -490,7 +1031,7 @@ func transformImpl(input string, options TransformOptions) TransformResult {
 		// Stop now if there were errors
 		if !log.HasErrors() {
 			// Compile the bundle
-			results = bundle.Compile(log, bundleOptions)
+			results, _ = bundle.Compile(log, bundleOptions)
 		}
 	}
 